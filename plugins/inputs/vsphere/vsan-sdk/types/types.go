@@ -0,0 +1,170 @@
+// Package types defines the request, response and data types of the vSAN management SOAP API, as
+// invoked through the sibling vsan-sdk/methods package.
+package types
+
+import "time"
+
+// ManagedObjectReference identifies a managed object on the vSAN management service; the vsan-sdk
+// analog of vim25/types.ManagedObjectReference.
+type ManagedObjectReference struct {
+	Type  string
+	Value string
+}
+
+// VsanPerfEntityType describes one performance entity type supported by VsanPerformanceManager.
+type VsanPerfEntityType struct {
+	Name   string   `xml:"name"`
+	Labels []string `xml:"labels,omitempty"`
+}
+
+// VsanPerfGetSupportedEntityTypes lists the performance entity types VsanPerformanceManager can report on.
+type VsanPerfGetSupportedEntityTypes struct {
+	This ManagedObjectReference `xml:"_this"`
+}
+
+type VsanPerfGetSupportedEntityTypesResponse struct {
+	Returnval []VsanPerfEntityType `xml:"returnval,omitempty"`
+}
+
+// VsanPerfQuerySpec scopes a VsanPerfQueryPerf call to one entity (or entity wildcard) and time range.
+type VsanPerfQuerySpec struct {
+	EntityRefId string     `xml:"entityRefId"`
+	StartTime   *time.Time `xml:"startTime,omitempty"`
+	EndTime     *time.Time `xml:"endTime,omitempty"`
+}
+
+// VsanPerfMetricId identifies a single performance counter within a VsanPerfEntityMetricCSV.
+type VsanPerfMetricId struct {
+	Label string `xml:"label"`
+}
+
+// VsanPerfMetricSeriesCSV is one counter's comma-separated sample values for an entity.
+type VsanPerfMetricSeriesCSV struct {
+	MetricId VsanPerfMetricId `xml:"metricId"`
+	Values   string           `xml:"values"`
+}
+
+// VsanPerfEntityMetricCSV is one entity's performance samples: a comma-separated SampleInfo time
+// series plus one VsanPerfMetricSeriesCSV per requested counter.
+type VsanPerfEntityMetricCSV struct {
+	EntityRefId string                    `xml:"entityRefId"`
+	SampleInfo  string                    `xml:"sampleInfo"`
+	Value       []VsanPerfMetricSeriesCSV `xml:"value,omitempty"`
+}
+
+// VsanPerfQueryPerf retrieves vSAN performance statistics for the given entities and time ranges.
+type VsanPerfQueryPerf struct {
+	This       ManagedObjectReference  `xml:"_this"`
+	QuerySpecs []VsanPerfQuerySpec     `xml:"querySpecs"`
+	Cluster    *ManagedObjectReference `xml:"cluster,omitempty"`
+}
+
+type VsanPerfQueryPerfResponse struct {
+	Returnval []VsanPerfEntityMetricCSV `xml:"returnval,omitempty"`
+}
+
+// VsanObjectSpaceSummary is the space used by one vSAN object type (vdisk, vmswap, namespace, ...).
+type VsanObjectSpaceSummary struct {
+	ObjType           string `xml:"objType"`
+	UsedB             int64  `xml:"usedB"`
+	PhysicalUsedB     int64  `xml:"physicalUsedB"`
+	ReservedCapacityB int64  `xml:"reservedCapacityB"`
+}
+
+// VsanSpaceUsageDetailResult is the detailed breakdown returned when SpaceDetailRequired is set on a
+// VsanQuerySpaceUsage call.
+type VsanSpaceUsageDetailResult struct {
+	PhysicalUsedB            int64                    `xml:"physicalUsedB"`
+	LogicalUsedB             int64                    `xml:"logicalUsedB"`
+	DedupMetadataSize        int64                    `xml:"dedupMetadataSize,omitempty"`
+	DedupAndCompressionRatio float64                  `xml:"dedupAndCompressionRatio,omitempty"`
+	SpaceUsageByObjectType   []VsanObjectSpaceSummary `xml:"spaceUsageByObjectType,omitempty"`
+}
+
+// VsanSpaceUsage is the overall vSAN datastore capacity report for a cluster.
+type VsanSpaceUsage struct {
+	FreeCapacityB  int64                       `xml:"freeCapacityB"`
+	TotalCapacityB int64                       `xml:"totalCapacityB"`
+	SpaceDetail    *VsanSpaceUsageDetailResult `xml:"spaceDetail,omitempty"`
+}
+
+// VsanQuerySpaceUsage retrieves vSAN datastore capacity usage for a cluster.
+type VsanQuerySpaceUsage struct {
+	This                ManagedObjectReference `xml:"_this"`
+	Cluster             ManagedObjectReference `xml:"cluster"`
+	SpaceDetailRequired *bool                  `xml:"spaceDetailRequired,omitempty"`
+}
+
+type VsanQuerySpaceUsageResponse struct {
+	Returnval VsanSpaceUsage `xml:"returnval"`
+}
+
+// VsanClusterHealthSystemStatusResult is the ClusterStatus subsystem of a cluster health summary.
+type VsanClusterHealthSystemStatusResult struct {
+	Status string `xml:"status"`
+}
+
+// VsanNetworkHealthResult is the NetworkHealth subsystem of a cluster health summary.
+type VsanNetworkHealthResult struct {
+	OverallHealth string `xml:"overallHealth"`
+}
+
+// VsanDiskHealthResult is the DiskHealth subsystem of a cluster health summary.
+type VsanDiskHealthResult struct {
+	OverallHealth string `xml:"overallHealth"`
+}
+
+// VsanLimitHealthResult is the LimitHealth subsystem of a cluster health summary.
+type VsanLimitHealthResult struct {
+	Health string `xml:"health"`
+}
+
+// VsanEncryptionHealthResult is the EncryptionHealth subsystem of a cluster health summary.
+type VsanEncryptionHealthResult struct {
+	Health string `xml:"health"`
+}
+
+// VsanPhysicalDisksHealthResult is the PhysicalDisksHealth subsystem of a cluster health summary.
+type VsanPhysicalDisksHealthResult struct {
+	OverallHealth string `xml:"overallHealth"`
+}
+
+// VsanObjectHealthResult is the ObjectHealth subsystem of a cluster health summary.
+type VsanObjectHealthResult struct {
+	OverallHealth string `xml:"overallHealth"`
+}
+
+// VsanClusterHostHealth is one host's health entry within a cluster health summary.
+type VsanClusterHostHealth struct {
+	Hostname string `xml:"hostname"`
+	NodeUuid string `xml:"nodeUuid"`
+	Health   string `xml:"health"`
+}
+
+// VsanClusterHealthSummary is the full vSAN cluster health report. Each subsystem pointer is only
+// populated if its name was requested via Fields and the running vSAN/vCenter version supports it.
+type VsanClusterHealthSummary struct {
+	OverallHealth            string                                `xml:"overallHealth"`
+	OverallHealthDescription string                                `xml:"overallHealthDescription,omitempty"`
+	ClusterStatus            *VsanClusterHealthSystemStatusResult `xml:"clusterStatus,omitempty"`
+	NetworkHealth            *VsanNetworkHealthResult             `xml:"networkHealth,omitempty"`
+	DiskHealth               *VsanDiskHealthResult                `xml:"diskHealth,omitempty"`
+	LimitHealth              *VsanLimitHealthResult               `xml:"limitHealth,omitempty"`
+	EncryptionHealth         *VsanEncryptionHealthResult          `xml:"encryptionHealth,omitempty"`
+	PhysicalDisksHealth      *VsanPhysicalDisksHealthResult       `xml:"physicalDisksHealth,omitempty"`
+	ObjectHealth             *VsanObjectHealthResult              `xml:"objectHealth,omitempty"`
+	HostHealth               []VsanClusterHostHealth              `xml:"hostHealth,omitempty"`
+}
+
+// VsanQueryVcClusterHealthSummary retrieves the vSAN health summary for a cluster. Fields restricts
+// the response to the requested subsystems.
+type VsanQueryVcClusterHealthSummary struct {
+	This           ManagedObjectReference `xml:"_this"`
+	Cluster        ManagedObjectReference `xml:"cluster"`
+	Fields         []string               `xml:"fields,omitempty"`
+	FetchFromCache *bool                  `xml:"fetchFromCache,omitempty"`
+}
+
+type VsanQueryVcClusterHealthSummaryResponse struct {
+	Returnval VsanClusterHealthSummary `xml:"returnval"`
+}
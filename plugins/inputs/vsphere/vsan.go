@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"github.com/influxdata/telegraf"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 
@@ -23,14 +25,26 @@ import (
 )
 
 const (
-	vsanNamespace           = "vsan"
-	vsanPath                = "/vsanHealth"
-	hwMarksKey              = "vsan-perf"
-	vsanPerfMetricsName     = "vsphere_cluster_vsan_performance"
-	vsanHealthMetricsName   = "vsphere_cluster_vsan_health"
-	vsanCapacityMetricsName = "vsphere_cluster_vsan_capacity"
+	vsanNamespace            = "vsan"
+	vsanPath                 = "/vsanHealth"
+	hwMarksKey               = "vsan-perf"
+	vmHwMarksKey             = "vsan-vm-perf"
+	vsanPerfMetricsName      = "vsphere_cluster_vsan_performance"
+	vsanVMPerfMetricsName    = "vsphere_vm_vsan_performance"
+	vsanHealthMetricsName    = "vsphere_cluster_vsan_health"
+	vsanHostHealthMetricName = "vsphere_host_vsan_health"
+	vsanCapacityMetricsName  = "vsphere_cluster_vsan_capacity"
+	vsanCapacityByTypeName   = "vsphere_cluster_vsan_capacity_by_type"
 )
 
+// vsanVMEntityTypes are the vSAN performance entity types that are scoped to a VM
+// rather than to a cluster/host/disk-group, e.g. "virtual-machine", "virtual-disk" and "vscsi".
+var vsanVMEntityTypes = map[string]bool{
+	"virtual-machine": true,
+	"virtual-disk":    true,
+	"vscsi":           true,
+}
+
 var (
 	perfManagerRef = vsantypes.ManagedObjectReference{
 		Type:  "VsanPerformanceManager",
@@ -61,6 +75,7 @@ func (e *Endpoint) collectVsan(ctx context.Context, resourceType string, acc tel
 	}
 	vimClient := client.Client.Client
 	metrics := e.getVsanPerfMetadata(ctx, vimClient, res)
+	vmMetrics := e.getVsanVMPerfMetadata(ctx, vimClient, res)
 	if err != nil {
 		log.Printf("D! [inputs.vsan]: Failed to get client: %s", err)
 		return err
@@ -70,14 +85,22 @@ func (e *Endpoint) collectVsan(ctx context.Context, resourceType string, acc tel
 		wg.Add(1)
 		go func(clusterObj objectRef) {
 			defer wg.Done()
-			e.collectVsanPerCluster(ctx, clusterObj, vimClient, metrics, acc)
+			e.collectVsanPerCluster(ctx, clusterObj, vimClient, metrics, vmMetrics, acc)
 		}(obj)
 	}
 	return nil
 }
 
 // collectVsanPerCluster is called by collectVsan
-func (e *Endpoint) collectVsanPerCluster(ctx context.Context, clusterRef objectRef, client *vim25.Client, metrics []string, acc telegraf.Accumulator) {
+func (e *Endpoint) collectVsanPerCluster(ctx context.Context, clusterRef objectRef, client *vim25.Client, metrics []string, vmMetrics []string, acc telegraf.Accumulator) {
+	// 0. Resolve a missing dcname by climbing the inventory tree, however many folders deep it is.
+	if clusterRef.dcname == "" {
+		if dcname, err := resolveDatacenterName(ctx, client, clusterRef.ref); err == nil {
+			clusterRef.dcname = dcname
+		} else {
+			log.Printf("D! [inputs.vsan]: Failed to resolve datacenter for cluster %s (%s): %s", clusterRef.name, clusterRef.ref.Value, err)
+		}
+	}
 	// 1. Construct a map for cmmds
 	cluster := object.NewClusterComputeResource(client, clusterRef.ref)
 	cmmds, err := getCmmdsMap(ctx, client, cluster)
@@ -99,10 +122,31 @@ func (e *Endpoint) collectVsanPerCluster(ctx context.Context, clusterRef objectR
 			acc.AddError(errors.New("While query vsan perf data:" + err.Error()))
 		}
 	}
+	if len(vmMetrics) > 0 {
+		if err = e.queryVMPerformance(ctx, vsanClient, client, clusterRef, vmMetrics, cmmds, acc); err != nil {
+			acc.AddError(errors.New("While query vsan VM perf data:" + err.Error()))
+		}
+	}
 }
 
-// getVsanPerfMetadata returns a string list of the performance entity types that will be queried.
+// getVsanPerfMetadata returns a string list of the cluster-scoped performance entity types that will be queried.
 func (e *Endpoint) getVsanPerfMetadata(ctx context.Context, client *vim25.Client, res *resourceKind) []string {
+	return e.filterVsanPerfEntityTypes(ctx, client, res, false)
+}
+
+// getVsanVMPerfMetadata returns a string list of the VM-scoped performance entity types that will be
+// queried. A dedicated "vsan_vm" resource kind (with its own vsan_vm_metric_include/
+// vsan_vm_metric_exclude config) doesn't exist yet, so for now VM-scoped entity types are filtered
+// through the same "vsan" resourceKind filters as cluster-scoped ones: like the rest of vSAN, this is
+// disabled by default, since the default filters match nothing.
+func (e *Endpoint) getVsanVMPerfMetadata(ctx context.Context, client *vim25.Client, res *resourceKind) []string {
+	return e.filterVsanPerfEntityTypes(ctx, client, res, true)
+}
+
+// filterVsanPerfEntityTypes fetches the supported vSAN performance entity types and filters them against
+// res.filters. When vmScoped is true, only entity types in vsanVMEntityTypes are considered; otherwise they
+// are excluded, keeping cluster-level and VM-level collection independent.
+func (e *Endpoint) filterVsanPerfEntityTypes(ctx context.Context, client *vim25.Client, res *resourceKind, vmScoped bool) []string {
 	vsanClient := client.NewServiceClient(vsanPath, vsanNamespace)
 	entityRes, err := vsanmethods.VsanPerfGetSupportedEntityTypes(ctx, vsanClient,
 		&vsantypes.VsanPerfGetSupportedEntityTypes{
@@ -116,15 +160,52 @@ func (e *Endpoint) getVsanPerfMetadata(ctx context.Context, client *vim25.Client
 	}
 	// Use the include & exclude configuration to filter all supported metrics
 	for _, entity := range entityRes.Returnval {
+		if vsanVMEntityTypes[entity.Name] != vmScoped {
+			continue
+		}
 		if res.filters.Match(entity.Name) {
 			metrics = append(metrics, entity.Name)
 		}
 	}
-	metrics = append(metrics)
 	log.Printf("D! vSan Metric: %v", metrics)
 	return metrics
 }
 
+// vsanHwMarksKey builds a high-water-mark store key scoped to a single vcenter+cluster pair, so that
+// per-cluster perf sampling watermarks don't clobber each other within an Endpoint.
+func vsanHwMarksKey(prefix, vcenter string, clusterRef objectRef) string {
+	return fmt.Sprintf("%s|%s|%s", prefix, vcenter, clusterRef.ref.Value)
+}
+
+// maxDatacenterSearchDepth bounds how many Folder levels resolveDatacenterName will climb looking for
+// an enclosing Datacenter, as a safety net against an unexpectedly malformed or cyclical inventory.
+const maxDatacenterSearchDepth = 32
+
+// resolveDatacenterName climbs a cluster's parent chain, however many Folder levels deep it is nested,
+// until it finds the enclosing Datacenter and returns its name.
+func resolveDatacenterName(ctx context.Context, client *vim25.Client, clusterRef types.ManagedObjectReference) (string, error) {
+	pc := property.DefaultCollector(client)
+	cur := clusterRef
+	for depth := 0; depth < maxDatacenterSearchDepth; depth++ {
+		var entity mo.ManagedEntity
+		if err := pc.RetrieveOne(ctx, cur, []string{"parent"}, &entity); err != nil {
+			return "", fmt.Errorf("fail to retrieve parent of %s: %v", cur.Value, err)
+		}
+		if entity.Parent == nil {
+			return "", fmt.Errorf("reached the top of the inventory without finding a datacenter for %s", clusterRef.Value)
+		}
+		cur = *entity.Parent
+		if cur.Type == "Datacenter" {
+			var dc mo.Datacenter
+			if err := pc.RetrieveOne(ctx, cur, []string{"name"}, &dc); err != nil {
+				return "", fmt.Errorf("fail to retrieve datacenter name for %s: %v", cur.Value, err)
+			}
+			return dc.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no datacenter found within %d folder levels for %s", maxDatacenterSearchDepth, clusterRef.Value)
+}
+
 // getCmmdsMap returns a map which maps a uuid to a CmmdsEntity
 func getCmmdsMap(ctx context.Context, client *vim25.Client, clusterObj *object.ClusterComputeResource) (map[string]CmmdsEntity, error) {
 	hosts, err := clusterObj.Hosts(ctx)
@@ -175,15 +256,40 @@ func getCmmdsMap(ctx context.Context, client *vim25.Client, clusterObj *object.C
 	return cmmdsMap, nil
 }
 
-// queryPerformance adds performance metrics to telegraf accumulator
+// vsanTagPopulator builds the tag set for a single performance sample given its entity name and uuid.
+type vsanTagPopulator func(tags map[string]string, entityName string, uuid string) map[string]string
+
+// queryPerformance adds cluster-scoped performance metrics to telegraf accumulator
 func (e *Endpoint) queryPerformance(ctx context.Context, vsanClient *soap.Client, clusterRef objectRef, metrics []string, cmmds map[string]CmmdsEntity, acc telegraf.Accumulator) error {
+	populateTags := func(tags map[string]string, entityName string, uuid string) map[string]string {
+		return populateCMMDSTags(tags, entityName, uuid, cmmds)
+	}
+	return e.queryPerfEntities(ctx, vsanClient, clusterRef, metrics, vsanPerfMetricsName, hwMarksKey, populateTags, acc)
+}
+
+// queryVMPerformance adds VM-scoped performance metrics (e.g. virtual-machine, virtual-disk, vscsi
+// entity types) to the telegraf accumulator as the vsphere_vm_vsan_performance measurement. Each sample
+// is correlated back to a VM name/UUID using CMMDS and, as a fallback, a vCenter SearchIndex lookup
+// by VM instance UUID.
+func (e *Endpoint) queryVMPerformance(ctx context.Context, vsanClient *soap.Client, client *vim25.Client, clusterRef objectRef, metrics []string, cmmds map[string]CmmdsEntity, acc telegraf.Accumulator) error {
+	populateTags := func(tags map[string]string, entityName string, uuid string) map[string]string {
+		return populateVMTags(tags, ctx, client, entityName, uuid, cmmds)
+	}
+	return e.queryPerfEntities(ctx, vsanClient, clusterRef, metrics, vsanVMPerfMetricsName, vmHwMarksKey, populateTags, acc)
+}
+
+// queryPerfEntities queries vSAN performance data for the given entity types and adds it to the
+// telegraf accumulator under measurementName, using populateTags to resolve each sample's tags and
+// hwMarksPrefix to track its own high-water mark independently of other entity scopes.
+func (e *Endpoint) queryPerfEntities(ctx context.Context, vsanClient *soap.Client, clusterRef objectRef, metrics []string, measurementName string, hwMarksPrefix string, populateTags vsanTagPopulator, acc telegraf.Accumulator) error {
+	hwMarkKey := vsanHwMarksKey(hwMarksPrefix, e.URL.Host, clusterRef)
 	end := time.Now().UTC()
-	start, ok := e.hwMarks.Get(hwMarksKey)
+	start, ok := e.hwMarks.Get(hwMarkKey)
 	if !ok {
 		// Look back 3 sampling periods by default
 		start = end.Add(metricLookback * time.Duration(-e.resourceKinds["vsan"].sampling) * time.Second)
 	}
-	log.Printf("D! [inputs.vsan]: Query vsan performance for time interval: %s ~ %s", start, end)
+	log.Printf("D! [inputs.vsan]: Query %s for time interval: %s ~ %s", measurementName, start, end)
 	latest := start
 
 	for _, entityRefId := range metrics {
@@ -214,7 +320,7 @@ func (e *Endpoint) queryPerformance(ctx context.Context, vsanClient *soap.Client
 			log.Printf("D! [inputs.vsphere][vSAN]\tSuccessfully Fetched data for Entity ==> %s:%d\n", em.EntityRefId, len(em.Value))
 			vals := strings.Split(em.EntityRefId, ":")
 			entityName, uuid := vals[0], vals[1]
-			tags := populateCMMDSTags(tags, entityName, uuid, cmmds)
+			tags := populateTags(tags, entityName, uuid)
 			var timeStamps []string
 			// 1. Construct a timestamp list from sample info
 			for _, t := range strings.Split(em.SampleInfo, ",") {
@@ -240,7 +346,7 @@ func (e *Endpoint) queryPerformance(ctx context.Context, vsanClient *soap.Client
 					if v, err := strconv.ParseFloat(values, 32); err == nil {
 						fields[field] = v
 					}
-					acc.AddFields(vsanPerfMetricsName, fields, tags, ts)
+					acc.AddFields(measurementName, fields, tags, ts)
 				}
 			}
 			if len(timeStamps) > 0 {
@@ -251,55 +357,142 @@ func (e *Endpoint) queryPerformance(ctx context.Context, vsanClient *soap.Client
 			}
 		}
 	}
-	e.hwMarks.Put(hwMarksKey, latest)
+	e.hwMarks.Put(hwMarkKey, latest)
 	return nil
 }
 
-// queryDiskUsage adds 'FreeCapacityB' and 'TotalCapacityB' metrics to telegraf accumulator
+// queryDiskUsage adds overall and per-object-type vSAN capacity metrics to the telegraf accumulator.
+// 'FreeCapacityB'/'TotalCapacityB' are emitted on vsphere_cluster_vsan_capacity alongside dedupe/
+// compression ratios and physical-vs-logical used bytes; per-object-type usage (vmswap, vdisk,
+// namespace, checksum overhead, filesystem overhead, dedupe metadata, ...) is broken out into its own
+// vsphere_cluster_vsan_capacity_by_type measurement tagged by object_type.
 func (e *Endpoint) queryDiskUsage(ctx context.Context, vsanClient *soap.Client, clusterRef objectRef, acc telegraf.Accumulator) error {
+	spaceDetailRequired := true
 	resp, err := vsanmethods.VsanQuerySpaceUsage(ctx, vsanClient,
 		&vsantypes.VsanQuerySpaceUsage{
-			This:    spaceManagerRef,
-			Cluster: vsantypes.ManagedObjectReference{clusterRef.ref.Type, clusterRef.ref.Value},
+			This:                spaceManagerRef,
+			Cluster:             vsantypes.ManagedObjectReference{clusterRef.ref.Type, clusterRef.ref.Value},
+			SpaceDetailRequired: &spaceDetailRequired,
 		})
 	if err != nil {
 		return err
 	}
+	tags := populateClusterTags(make(map[string]string), clusterRef, e.URL.Host)
+
 	fields := make(map[string]interface{})
 	fields["FreeCapacityB"] = resp.Returnval.FreeCapacityB
 	fields["TotalCapacityB"] = resp.Returnval.TotalCapacityB
-	tags := populateClusterTags(make(map[string]string), clusterRef, e.URL.Host)
+	if detail := resp.Returnval.SpaceDetail; detail != nil {
+		fields["PhysicalUsedB"] = detail.PhysicalUsedB
+		fields["LogicalUsedB"] = detail.LogicalUsedB
+		if detail.DedupMetadataSize != 0 {
+			fields["DedupMetadataSizeB"] = detail.DedupMetadataSize
+		}
+		if detail.DedupAndCompressionRatio != 0 {
+			fields["DedupAndCompressionRatio"] = detail.DedupAndCompressionRatio
+		}
+		for _, usage := range detail.SpaceUsageByObjectType {
+			objTypeTags := make(map[string]string)
+			for k, v := range tags {
+				objTypeTags[k] = v
+			}
+			objTypeTags["object_type"] = usage.ObjType
+			acc.AddFields(vsanCapacityByTypeName, capacityByTypeFields(usage), objTypeTags)
+		}
+	}
 	acc.AddFields(vsanCapacityMetricsName, fields, tags)
 	return nil
 }
 
-// queryDiskUsage adds 'OverallHealth' metric to telegraf accumulator
+// capacityByTypeFields builds the field set for a single vsphere_cluster_vsan_capacity_by_type sample.
+func capacityByTypeFields(usage vsantypes.VsanObjectSpaceSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"UsedB":             usage.UsedB,
+		"PhysicalUsedB":     usage.PhysicalUsedB,
+		"ReservedCapacityB": usage.ReservedCapacityB,
+	}
+}
+
+// healthColorToInt maps the red/yellow/green/unknown vSAN health strings to a numeric scale so they
+// can be graphed: red=2, yellow=1, green=0, unknown=-1.
+func healthColorToInt(health string) int {
+	switch health {
+	case "red":
+		return 2
+	case "yellow":
+		return 1
+	case "green":
+		return 0
+	default:
+		return -1
+	}
+}
+
+// queryHealthSummary adds cluster-wide and per-host vSAN health metrics to the telegraf accumulator.
+// OverallHealth is broken down into its individual subsystems (network, disk, capacity limits,
+// encryption, physical disks, objects, ...) on vsphere_cluster_vsan_health, and per-host health is
+// emitted as vsphere_host_vsan_health so operators can tell what kind of problem is driving a
+// red/yellow status instead of a single opaque color.
 func (e *Endpoint) queryHealthSummary(ctx context.Context, vsanClient *soap.Client, clusterRef objectRef, acc telegraf.Accumulator) error {
 	fetchFromCache := true
 	resp, err := vsanmethods.VsanQueryVcClusterHealthSummary(ctx, vsanClient,
 		&vsantypes.VsanQueryVcClusterHealthSummary{
-			This:           healthSystemRef,
-			Cluster:        vsantypes.ManagedObjectReference{clusterRef.ref.Type, clusterRef.ref.Value},
-			Fields:         []string{"overallHealth", "overallHealthDescription"},
+			This:    healthSystemRef,
+			Cluster: vsantypes.ManagedObjectReference{clusterRef.ref.Type, clusterRef.ref.Value},
+			Fields: []string{
+				"overallHealth", "overallHealthDescription", "clusterStatus", "hostHealth",
+				"networkHealth", "diskHealth", "limitHealth", "encryptionHealth",
+				"physicalDisksHealth", "objectHealth",
+			},
 			FetchFromCache: &fetchFromCache,
 		})
 	if err != nil {
 		return err
 	}
-	fields := make(map[string]interface{})
-	overallHealth := resp.Returnval.OverallHealth
-	switch overallHealth {
-	case "red":
-		fields["OverallHealth"] = 2
-	case "yellow":
-		fields["OverallHealth"] = 1
-	case "green":
-		fields["OverallHealth"] = 0
-	default:
-		fields["OverallHealth"] = -1
-	}
 	tags := populateClusterTags(make(map[string]string), clusterRef, e.URL.Host)
+
+	// Each subsystem is only requested via Fields on a best-effort basis: VsanQueryVcClusterHealthSummary
+	// may leave a subsystem's result nil if it wasn't populated by this vSAN/vCenter version, so every
+	// pointer is nil-checked before use instead of assuming all requested fields came back.
+	fields := map[string]interface{}{
+		"OverallHealth":            healthColorToInt(resp.Returnval.OverallHealth),
+		"OverallHealthDescription": resp.Returnval.OverallHealthDescription,
+	}
+	if status := resp.Returnval.ClusterStatus; status != nil {
+		fields["ClusterHealth"] = healthColorToInt(status.Status)
+	}
+	if health := resp.Returnval.NetworkHealth; health != nil {
+		fields["NetworkHealth"] = healthColorToInt(health.OverallHealth)
+	}
+	if health := resp.Returnval.DiskHealth; health != nil {
+		fields["DiskHealth"] = healthColorToInt(health.OverallHealth)
+	}
+	if health := resp.Returnval.LimitHealth; health != nil {
+		fields["LimitHealth"] = healthColorToInt(health.Health)
+	}
+	if health := resp.Returnval.EncryptionHealth; health != nil {
+		fields["EncryptionHealth"] = healthColorToInt(health.Health)
+	}
+	if health := resp.Returnval.PhysicalDisksHealth; health != nil {
+		fields["PhysicalDisksHealth"] = healthColorToInt(health.OverallHealth)
+	}
+	if health := resp.Returnval.ObjectHealth; health != nil {
+		fields["ObjectHealth"] = healthColorToInt(health.OverallHealth)
+	}
 	acc.AddFields(vsanHealthMetricsName, fields, tags)
+
+	for _, host := range resp.Returnval.HostHealth {
+		hostTags := make(map[string]string)
+		for k, v := range tags {
+			hostTags[k] = v
+		}
+		hostTags["hostname"] = host.Hostname
+		hostTags["esxi_uuid"] = host.NodeUuid
+		hostFields := map[string]interface{}{
+			"OverallHealth": healthColorToInt(host.Health),
+		}
+		acc.AddFields(vsanHostHealthMetricName, hostFields, hostTags)
+	}
 	return nil
 }
 
@@ -378,6 +571,39 @@ func populateCMMDSTags(tags map[string]string, entityName string, uuid string, c
 	return newTags
 }
 
+// populateVMTags takes in a tag map, makes a copy, adds VM name/UUID tags resolved via cmmds and returns
+// the copy. virtual-disk and vscsi entities are reported as "<vmUuid>|<diskId>", while virtual-machine
+// entities are reported by vmUuid alone.
+func populateVMTags(tags map[string]string, ctx context.Context, client *vim25.Client, entityName string, uuid string, cmmds map[string]CmmdsEntity) map[string]string {
+	newTags := make(map[string]string)
+	// deep copy
+	for k, v := range tags {
+		newTags[k] = v
+	}
+	vmUuid := strings.Split(uuid, "|")[0]
+	newTags["vmUuid"] = vmUuid
+	if e, ok := cmmds[vmUuid]; ok {
+		if c, ok := e.Content.(map[string]interface{}); ok {
+			if name, ok := c["vmName"].(string); ok {
+				newTags["vmName"] = name
+			}
+		}
+	}
+	if _, ok := newTags["vmName"]; !ok {
+		// vmUuid is the VM's instance UUID, not a vCenter MoRef value, so resolve it through
+		// SearchIndex.FindByUuid rather than constructing a VirtualMachine reference from it directly.
+		isInstanceUuid := true
+		if ref, err := object.NewSearchIndex(client).FindByUuid(ctx, nil, vmUuid, true, &isInstanceUuid); err == nil && ref != nil {
+			if vm, ok := ref.(*object.VirtualMachine); ok {
+				if name, err := vm.ObjectName(ctx); err == nil && name != "" {
+					newTags["vmName"] = name
+				}
+			}
+		}
+	}
+	return newTags
+}
+
 // versionSupportsVsan returns if vsan is supported for a given version, that is version >= 5.5.
 func versionSupportsVsan(version string) bool {
 	v := strings.Split(version, ".")
@@ -0,0 +1,269 @@
+package vsphere
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	vsantypes "github.com/influxdata/telegraf/plugins/inputs/vsphere/vsan-sdk/types"
+)
+
+func TestPopulateVMTags(t *testing.T) {
+	cmmds := map[string]CmmdsEntity{
+		"vm-uuid-1": {
+			UUID: "vm-uuid-1",
+			Type: "VM",
+			Content: map[string]interface{}{
+				"vmName": "web-01",
+			},
+		},
+	}
+
+	tags := populateVMTags(make(map[string]string), context.Background(), nil, "virtual-machine", "vm-uuid-1", cmmds)
+
+	if tags["vmUuid"] != "vm-uuid-1" {
+		t.Errorf("expected vmUuid tag %q, got %q", "vm-uuid-1", tags["vmUuid"])
+	}
+	if tags["vmName"] != "web-01" {
+		t.Errorf("expected vmName tag %q, got %q", "web-01", tags["vmName"])
+	}
+}
+
+func TestPopulateVMTagsDisk(t *testing.T) {
+	cmmds := map[string]CmmdsEntity{
+		"vm-uuid-2": {
+			UUID: "vm-uuid-2",
+			Type: "VM",
+			Content: map[string]interface{}{
+				"vmName": "db-01",
+			},
+		},
+	}
+
+	// virtual-disk and vscsi entities carry the vmUuid as the first '|'-delimited segment of the uuid.
+	tags := populateVMTags(make(map[string]string), context.Background(), nil, "virtual-disk", "vm-uuid-2|disk-0", cmmds)
+
+	if tags["vmUuid"] != "vm-uuid-2" {
+		t.Errorf("expected vmUuid tag %q, got %q", "vm-uuid-2", tags["vmUuid"])
+	}
+	if tags["vmName"] != "db-01" {
+		t.Errorf("expected vmName tag %q, got %q", "db-01", tags["vmName"])
+	}
+}
+
+func TestPopulateVMTagsSearchIndexFallback(t *testing.T) {
+	simulator.Test(func(ctx context.Context, client *vim25.Client) {
+		finder := find.NewFinder(client)
+		vms, err := finder.VirtualMachineList(ctx, "*")
+		if err != nil || len(vms) == 0 {
+			t.Fatalf("fail to find any simulator VMs: %v", err)
+		}
+		vm := vms[0]
+
+		var mvm mo.VirtualMachine
+		if err := vm.Properties(ctx, vm.Reference(), []string{"config.instanceUuid", "name"}, &mvm); err != nil {
+			t.Fatalf("fail to read vm properties: %v", err)
+		}
+		if mvm.Config == nil || mvm.Config.InstanceUuid == "" {
+			t.Skip("simulator VM has no instance uuid")
+		}
+
+		// Not in cmmds, so populateVMTags must fall back to SearchIndex.FindByUuid.
+		tags := populateVMTags(make(map[string]string), ctx, client, "virtual-machine", mvm.Config.InstanceUuid, map[string]CmmdsEntity{})
+
+		if tags["vmName"] != mvm.Name {
+			t.Errorf("expected vmName tag %q resolved via SearchIndex fallback, got %q", mvm.Name, tags["vmName"])
+		}
+	})
+}
+
+func TestPopulateCMMDSTagsDisk(t *testing.T) {
+	cmmds := map[string]CmmdsEntity{
+		"host-uuid-1": {
+			UUID: "host-uuid-1",
+			Type: "HOSTNAME",
+			Content: map[string]interface{}{
+				"hostname": "esx-01.example.com",
+			},
+		},
+		"disk-uuid-1": {
+			UUID:  "disk-uuid-1",
+			Type:  "DISK",
+			Owner: "host-uuid-1",
+			Content: map[string]interface{}{
+				"devName": "naa.5001",
+				"isSsd":   float64(1),
+			},
+		},
+	}
+
+	tags := populateCMMDSTags(make(map[string]string), "capacity-disk", "disk-uuid-1", cmmds)
+
+	if tags["hostname"] != "esx-01.example.com" {
+		t.Errorf("expected hostname tag %q, got %q", "esx-01.example.com", tags["hostname"])
+	}
+	if tags["deviceName"] != "naa.5001" {
+		t.Errorf("expected deviceName tag %q, got %q", "naa.5001", tags["deviceName"])
+	}
+	if _, ok := tags["ssdUuid"]; ok {
+		t.Errorf("did not expect ssdUuid tag for an SSD device")
+	}
+}
+
+func TestPopulateCMMDSTagsUnknownEntity(t *testing.T) {
+	tags := populateCMMDSTags(make(map[string]string), "unknown-entity", "some-uuid", map[string]CmmdsEntity{})
+	if tags["uuid"] != "some-uuid" {
+		t.Errorf("expected fallback uuid tag %q, got %q", "some-uuid", tags["uuid"])
+	}
+}
+
+func TestVsanHwMarksKey(t *testing.T) {
+	clusterA := objectRef{ref: types.ManagedObjectReference{Type: "ClusterComputeResource", Value: "domain-c1"}}
+	clusterB := objectRef{ref: types.ManagedObjectReference{Type: "ClusterComputeResource", Value: "domain-c2"}}
+
+	keyA := vsanHwMarksKey(hwMarksKey, "vc1.example.com", clusterA)
+	keyB := vsanHwMarksKey(hwMarksKey, "vc1.example.com", clusterB)
+	keyAOtherVcenter := vsanHwMarksKey(hwMarksKey, "vc2.example.com", clusterA)
+
+	if keyA == keyB {
+		t.Errorf("expected different clusters on the same vcenter to get different hwMarks keys, both got %q", keyA)
+	}
+	if keyA == keyAOtherVcenter {
+		t.Errorf("expected the same cluster moid on different vcenters to get different hwMarks keys, both got %q", keyA)
+	}
+	if vsanHwMarksKey(hwMarksKey, "vc1.example.com", clusterA) != keyA {
+		t.Errorf("expected vsanHwMarksKey to be deterministic for the same inputs")
+	}
+}
+
+func TestResolveDatacenterNameNestedFolders(t *testing.T) {
+	simulator.Test(func(ctx context.Context, client *vim25.Client) {
+		finder := find.NewFinder(client)
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatalf("fail to find default datacenter: %v", err)
+		}
+		finder.SetDatacenter(dc)
+
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatalf("fail to get datacenter folders: %v", err)
+		}
+		level1, err := folders.HostFolder.CreateFolder(ctx, "level1")
+		if err != nil {
+			t.Fatalf("fail to create nested folder: %v", err)
+		}
+		level2, err := level1.CreateFolder(ctx, "level2")
+		if err != nil {
+			t.Fatalf("fail to create nested folder: %v", err)
+		}
+		cluster, err := level2.CreateCluster(ctx, "nested-cluster", types.ClusterConfigSpecEx{})
+		if err != nil {
+			t.Fatalf("fail to create cluster: %v", err)
+		}
+
+		name, err := resolveDatacenterName(ctx, client, cluster.Reference())
+		if err != nil {
+			t.Fatalf("resolveDatacenterName() returned error: %v", err)
+		}
+		if name != dc.Name() {
+			t.Errorf("resolveDatacenterName() = %q, want %q", name, dc.Name())
+		}
+	})
+}
+
+func TestHealthColorToInt(t *testing.T) {
+	tests := []struct {
+		health string
+		want   int
+	}{
+		{"red", 2},
+		{"yellow", 1},
+		{"green", 0},
+		{"unknown", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := healthColorToInt(tt.health); got != tt.want {
+			t.Errorf("healthColorToInt(%q) = %d, want %d", tt.health, got, tt.want)
+		}
+	}
+}
+
+func TestCapacityByTypeFields(t *testing.T) {
+	usage := vsantypes.VsanObjectSpaceSummary{
+		ObjType:           "vdisk",
+		UsedB:             1024,
+		PhysicalUsedB:     512,
+		ReservedCapacityB: 2048,
+	}
+
+	fields := capacityByTypeFields(usage)
+
+	want := map[string]interface{}{
+		"UsedB":             int64(1024),
+		"PhysicalUsedB":     int64(512),
+		"ReservedCapacityB": int64(2048),
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("capacityByTypeFields() = %v, want %v", fields, want)
+	}
+}
+
+func TestHealthSummarySubsystemFields(t *testing.T) {
+	summary := vsantypes.VsanClusterHealthSummary{
+		OverallHealth:            "yellow",
+		OverallHealthDescription: "Some issues found",
+		ClusterStatus:            &vsantypes.VsanClusterHealthSystemStatusResult{Status: "green"},
+		NetworkHealth:            &vsantypes.VsanNetworkHealthResult{OverallHealth: "red"},
+		DiskHealth:               &vsantypes.VsanDiskHealthResult{OverallHealth: "green"},
+		LimitHealth:              &vsantypes.VsanLimitHealthResult{Health: "yellow"},
+		EncryptionHealth:         &vsantypes.VsanEncryptionHealthResult{Health: "green"},
+		PhysicalDisksHealth:      &vsantypes.VsanPhysicalDisksHealthResult{OverallHealth: "green"},
+		ObjectHealth:             &vsantypes.VsanObjectHealthResult{OverallHealth: "yellow"},
+		HostHealth: []vsantypes.VsanClusterHostHealth{
+			{Hostname: "esx-01.example.com", NodeUuid: "host-uuid-1", Health: "green"},
+		},
+	}
+
+	if got := healthColorToInt(summary.ClusterStatus.Status); got != 0 {
+		t.Errorf("ClusterStatus.Status: got %d, want 0", got)
+	}
+	if got := healthColorToInt(summary.NetworkHealth.OverallHealth); got != 2 {
+		t.Errorf("NetworkHealth.OverallHealth: got %d, want 2", got)
+	}
+	if got := healthColorToInt(summary.LimitHealth.Health); got != 1 {
+		t.Errorf("LimitHealth.Health: got %d, want 1", got)
+	}
+	if len(summary.HostHealth) != 1 || summary.HostHealth[0].Hostname != "esx-01.example.com" {
+		t.Errorf("HostHealth: got %+v", summary.HostHealth)
+	}
+}
+
+func TestPopulateClusterTags(t *testing.T) {
+	clusterRef := objectRef{
+		ref:    types.ManagedObjectReference{Type: "ClusterComputeResource", Value: "domain-c1"},
+		name:   "prod-cluster",
+		dcname: "DC1",
+	}
+
+	tags := populateClusterTags(map[string]string{"existing": "tag"}, clusterRef, "vc.example.com")
+
+	want := map[string]string{
+		"existing":    "tag",
+		"vcenter":     "vc.example.com",
+		"dcname":      "DC1",
+		"clustername": "prod-cluster",
+		"moid":        "domain-c1",
+		"source":      "prod-cluster",
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("populateClusterTags() = %v, want %v", tags, want)
+	}
+}